@@ -0,0 +1,133 @@
+package mobile
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// DefaultCacheSize is the number of resolved Devices Resolver() memoizes by
+// default; see WithCacheSize to change it and WithCacheSize(0) to disable
+// caching entirely.
+const DefaultCacheSize = 4096
+
+// CacheStats reports cumulative activity for a Cache, for observability.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// Cache is a fixed-size LRU cache mapping the request headers the built-in
+// resolvers read (see cacheKey) to a resolved Device, avoiding the repeated
+// UA scanning and Client Hints parsing resolveDevice/resolveFromClientHints
+// would otherwise do on every request for the same client. Devices are
+// immutable once resolved, so cached entries are safe to share across
+// goroutines. The zero value is not usable; construct one with NewCache. A
+// nil *Cache is valid and simply disables caching, so WithCacheSize(0) can
+// hand one to Resolver() without a special case.
+//
+// If you supply a custom resolver via WithResolver that reads request
+// headers beyond those cacheKey covers, either disable caching with
+// WithCacheSize(0) or key your own Cache-like memoization on whatever your
+// resolver actually consults.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently used
+
+	hits, misses, evictions uint64
+}
+
+type cacheEntry struct {
+	key    string
+	device Device
+}
+
+// NewCache returns a Cache holding at most capacity entries. A capacity <= 0
+// returns nil, which Resolver() treats as "caching disabled".
+func NewCache(capacity int) *Cache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &Cache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// cacheKey derives the Cache lookup key for a request from every header the
+// built-in resolvers read: User-Agent plus the legacy classification headers
+// (X-Wap-Profile, Profile, Accept) consulted by classifyDevice, and the
+// Client Hints headers (Sec-CH-UA*) resolveFromClientHints prefers when
+// present. Two requests differing in any of these can resolve to different
+// Devices, so all of them must be part of the key — otherwise a cache hit
+// could serve one request's Device to another, e.g. a frozen/identical
+// User-Agent with differing Sec-CH-UA-Mobile values.
+func cacheKey(header http.Header) string {
+	return strings.Join([]string{
+		header.Get("User-Agent"),
+		header.Get(XwapProfile),
+		header.Get(Profile),
+		header.Get("Accept"),
+		header.Get(HeaderSecCHUA),
+		header.Get(HeaderSecCHUAMobile),
+		header.Get(HeaderSecCHUAPlatform),
+		header.Get(HeaderSecCHUAPlatformVer),
+		header.Get(HeaderSecCHUAModel),
+	}, "\x00")
+}
+
+func (c *Cache) get(key string) (Device, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	c.hits++
+	return el.Value.(*cacheEntry).device, true
+}
+
+func (c *Cache) put(key string, d Device) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		el.Value.(*cacheEntry).device = d
+		return
+	}
+
+	el := c.order.PushFront(&cacheEntry{key: key, device: d})
+	c.entries[key] = el
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// Stats returns cumulative hit/miss/eviction counts. Safe to call on a nil
+// Cache, which always reports zeroes.
+func (c *Cache) Stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions}
+}
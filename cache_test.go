@@ -0,0 +1,98 @@
+package mobile
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCacheKeyDistinguishesClientHints(t *testing.T) {
+	android := http.Header{
+		"User-Agent":         []string{"frozen-ua"},
+		"Sec-Ch-Ua-Mobile":   []string{"?1"},
+		"Sec-Ch-Ua-Platform": []string{`"Android"`},
+	}
+	windows := http.Header{
+		"User-Agent":         []string{"frozen-ua"},
+		"Sec-Ch-Ua-Mobile":   []string{"?0"},
+		"Sec-Ch-Ua-Platform": []string{`"Windows"`},
+	}
+
+	if cacheKey(android) == cacheKey(windows) {
+		t.Fatal("cacheKey must differ when Sec-CH-UA-Mobile/Platform differ on an identical frozen User-Agent")
+	}
+}
+
+func TestCacheKeyDistinguishesLegacyHeaders(t *testing.T) {
+	base := http.Header{"User-Agent": []string{"same-ua"}}
+
+	withProfile := base.Clone()
+	withProfile.Set(Profile, "some-profile")
+
+	withWapAccept := base.Clone()
+	withWapAccept.Set("Accept", "text/vnd.wap.wml")
+
+	if cacheKey(base) == cacheKey(withProfile) {
+		t.Error("cacheKey must differ when the Profile header differs")
+	}
+	if cacheKey(base) == cacheKey(withWapAccept) {
+		t.Error("cacheKey must differ when the Accept header differs")
+	}
+}
+
+func TestCacheGetPutAndEviction(t *testing.T) {
+	c := NewCache(2)
+
+	d1 := &device{mobile: true, platform: Android}
+	d2 := &device{tablet: true, platform: Ipad}
+	d3 := &device{normal: true, platform: Unknown}
+
+	c.put("a", d1)
+	c.put("b", d2)
+
+	if got, ok := c.get("a"); !ok || got != Device(d1) {
+		t.Fatalf("expected cache hit for key a")
+	}
+	if stats := c.Stats(); stats.Hits != 1 || stats.Misses != 0 {
+		t.Fatalf("stats = %+v, want 1 hit, 0 misses", stats)
+	}
+
+	// "a" is now most-recently-used; inserting a third entry should evict "b".
+	c.put("c", d3)
+
+	if _, ok := c.get("b"); ok {
+		t.Fatal("expected key b to have been evicted")
+	}
+	if stats := c.Stats(); stats.Evictions != 1 {
+		t.Fatalf("stats = %+v, want 1 eviction", stats)
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("expected key a to survive eviction as the most recently used entry")
+	}
+	if _, ok := c.get("c"); !ok {
+		t.Fatal("expected key c to be present")
+	}
+}
+
+func TestNilCacheDisablesCaching(t *testing.T) {
+	var c *Cache
+	c.put("a", &device{})
+
+	if _, ok := c.get("a"); ok {
+		t.Fatal("a nil Cache must never report a hit")
+	}
+	if stats := c.Stats(); stats != (CacheStats{}) {
+		t.Fatalf("stats = %+v, want zero value", stats)
+	}
+}
+
+func TestWithCacheSizeZeroDisablesCaching(t *testing.T) {
+	if c := NewCache(0); c != nil {
+		t.Fatal("NewCache(0) must return nil")
+	}
+
+	cfg := &resolverConfig{cache: NewCache(DefaultCacheSize)}
+	WithCacheSize(0)(cfg)
+	if cfg.cache != nil {
+		t.Fatal("WithCacheSize(0) must leave Resolver() without a cache")
+	}
+}
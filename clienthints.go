@@ -0,0 +1,142 @@
+package mobile
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Platform values produced by the Client Hints resolver for desktop-class
+// operating systems, which the legacy User-Agent sniffer never needed a
+// constant for.
+const (
+	Windows  = "windows"
+	MacOS    = "macos"
+	ChromeOS = "chromeos"
+)
+
+// User-Agent Client Hints request headers understood by the built-in
+// resolver, and the response headers used to advertise them.
+const (
+	HeaderSecCHUA            = "Sec-CH-UA"
+	HeaderSecCHUAMobile      = "Sec-CH-UA-Mobile"
+	HeaderSecCHUAPlatform    = "Sec-CH-UA-Platform"
+	HeaderSecCHUAPlatformVer = "Sec-CH-UA-Platform-Version"
+	HeaderSecCHUAModel       = "Sec-CH-UA-Model"
+
+	AcceptCHHeader   = "Accept-CH"
+	CriticalCHHeader = "Critical-CH"
+)
+
+// clientHintsHeaders lists every hint the built-in resolver understands,
+// advertised via Accept-CH so browsers start sending them on later requests.
+var clientHintsHeaders = []string{
+	HeaderSecCHUA,
+	HeaderSecCHUAMobile,
+	HeaderSecCHUAPlatform,
+	HeaderSecCHUAPlatformVer,
+	HeaderSecCHUAModel,
+}
+
+// criticalClientHints lists only the low-entropy hints resolveFromClientHints
+// actually needs to classify a request (mobile vs. not, coarse platform).
+// These are advertised via Critical-CH, which forces a navigation restart
+// on the first request so the hints arrive in time to be used. The
+// high-entropy Sec-CH-UA-Platform-Version and Sec-CH-UA-Model are nice to
+// have for richer OS reporting but aren't required for correct resolution,
+// so they stay out of Critical-CH to avoid paying that round-trip for them.
+var criticalClientHints = []string{
+	HeaderSecCHUAMobile,
+	HeaderSecCHUAPlatform,
+}
+
+// secCHUABrandRE matches one `"Brand";v="Version"` pair from the structured
+// header list carried in Sec-CH-UA.
+var secCHUABrandRE = regexp.MustCompile(`"([^"]+)";v="([^"]+)"`)
+
+// advertiseClientHints sets Accept-CH (the full hint set the built-in
+// resolver understands) and Critical-CH (only the subset it needs to
+// classify a request) on the response.
+func advertiseClientHints(c *gin.Context) {
+	c.Header(AcceptCHHeader, strings.Join(clientHintsHeaders, ", "))
+	c.Header(CriticalCHHeader, strings.Join(criticalClientHints, ", "))
+}
+
+// resolveFromClientHints builds a Device from User-Agent Client Hints
+// headers. ok is false when the request carried neither Sec-CH-UA-Mobile nor
+// Sec-CH-UA-Platform, so callers should fall back to legacy User-Agent
+// sniffing.
+func resolveFromClientHints(header http.Header) (d *device, ok bool) {
+	mobileHint := header.Get(HeaderSecCHUAMobile)
+	platformHint := unquote(header.Get(HeaderSecCHUAPlatform))
+	if mobileHint == "" && platformHint == "" {
+		return nil, false
+	}
+
+	d = &device{}
+	isMobile := mobileHint == "?1"
+
+	switch platformHint {
+	case "Android":
+		d.platform, d.osName = Android, "Android"
+		d.mobile, d.normal = isMobile, !isMobile
+	case "iOS":
+		d.platform, d.osName = Ios, "iOS"
+		d.mobile, d.normal = isMobile, !isMobile
+	case "Windows":
+		d.platform, d.osName = Windows, "Windows"
+		d.normal = true
+	case "macOS":
+		d.platform, d.osName = MacOS, "macOS"
+		d.normal = true
+	case "Chrome OS":
+		d.platform, d.osName = ChromeOS, "Chrome OS"
+		d.normal = true
+	default:
+		d.platform = Unknown
+		d.mobile, d.normal = isMobile, !isMobile
+	}
+
+	if v := unquote(header.Get(HeaderSecCHUAPlatformVer)); v != "" {
+		d.osVersion = v
+	}
+
+	if name, version, found := parseSecCHUABrand(header.Get(HeaderSecCHUA)); found {
+		d.browserName = name
+		d.browserVersion = version
+	}
+
+	return d, true
+}
+
+// parseSecCHUABrand picks a non-GREASE brand out of the structured header
+// list carried in Sec-CH-UA, e.g. `"Not)A;Brand";v="8", "Chromium";v="116",
+// "Google Chrome";v="116"`. GREASE brands (used to prevent UA string
+// ossification) always contain "Not" and are skipped; the generic
+// "Chromium" brand is only returned when no more specific brand is present.
+func parseSecCHUABrand(header string) (name string, version string, ok bool) {
+	var chromiumName, chromiumVersion string
+	for _, m := range secCHUABrandRE.FindAllStringSubmatch(header, -1) {
+		brand, ver := m[1], m[2]
+		switch {
+		case strings.Contains(brand, "Not"):
+			continue // GREASE brand
+		case brand == "Chromium":
+			chromiumName, chromiumVersion = brand, ver
+		default:
+			return brand, ver, true
+		}
+	}
+	if chromiumName != "" {
+		return chromiumName, chromiumVersion, true
+	}
+	return "", "", false
+}
+
+// unquote strips the double quotes structured Client Hints values are
+// wrapped in, e.g. `"Android"` -> `Android`.
+func unquote(s string) string {
+	return strings.Trim(s, `"`)
+}
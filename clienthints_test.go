@@ -0,0 +1,85 @@
+package mobile
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestResolveFromClientHints(t *testing.T) {
+	cases := []struct {
+		name           string
+		header         http.Header
+		wantOK         bool
+		wantMobile     bool
+		wantNormal     bool
+		wantPlatform   string
+		wantBrowser    string
+		wantBrowserVer string
+	}{
+		{
+			name:   "no hints falls back to UA sniffing",
+			header: http.Header{"User-Agent": []string{"some-agent"}},
+			wantOK: false,
+		},
+		{
+			name: "android mobile",
+			header: http.Header{
+				"Sec-Ch-Ua-Mobile":   []string{"?1"},
+				"Sec-Ch-Ua-Platform": []string{`"Android"`},
+				"Sec-Ch-Ua":          []string{`"Not)A;Brand";v="8", "Chromium";v="116", "Google Chrome";v="116"`},
+			},
+			wantOK:         true,
+			wantMobile:     true,
+			wantPlatform:   Android,
+			wantBrowser:    "Google Chrome",
+			wantBrowserVer: "116",
+		},
+		{
+			name: "windows desktop",
+			header: http.Header{
+				"Sec-Ch-Ua-Mobile":   []string{"?0"},
+				"Sec-Ch-Ua-Platform": []string{`"Windows"`},
+			},
+			wantOK:       true,
+			wantNormal:   true,
+			wantPlatform: Windows,
+		},
+		{
+			name: "GREASE plus Chromium brand list falls back to Chromium",
+			header: http.Header{
+				"Sec-Ch-Ua-Mobile":   []string{"?0"},
+				"Sec-Ch-Ua-Platform": []string{`"macOS"`},
+				"Sec-Ch-Ua":          []string{`"Not)A;Brand";v="8", "Chromium";v="116"`},
+			},
+			wantOK:         true,
+			wantNormal:     true,
+			wantPlatform:   MacOS,
+			wantBrowser:    "Chromium",
+			wantBrowserVer: "116",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, ok := resolveFromClientHints(tc.header)
+			if ok != tc.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if d.mobile != tc.wantMobile {
+				t.Errorf("mobile = %v, want %v", d.mobile, tc.wantMobile)
+			}
+			if d.normal != tc.wantNormal {
+				t.Errorf("normal = %v, want %v", d.normal, tc.wantNormal)
+			}
+			if d.platform != tc.wantPlatform {
+				t.Errorf("platform = %q, want %q", d.platform, tc.wantPlatform)
+			}
+			if d.browserName != tc.wantBrowser || d.browserVersion != tc.wantBrowserVer {
+				t.Errorf("browser = (%q, %q), want (%q, %q)", d.browserName, d.browserVersion, tc.wantBrowser, tc.wantBrowserVer)
+			}
+		})
+	}
+}
@@ -52,28 +52,167 @@ type device struct {
 	mobile   bool
 	tablet   bool
 	platform string
+
+	browserName    string
+	browserVersion string
+	osName         string
+	osVersion      string
+	bot            bool
 }
 
-type Device interface {
+// BasicDevice is the original, coarse classification of a client: its device
+// class (mobile/tablet/normal) and platform. It is kept as its own interface
+// so that code written against the pre-UA-parsing API keeps compiling against
+// the richer Device below.
+type BasicDevice interface {
 	Normal() bool
 	Mobile() bool
 	Tablet() bool
 	Platform() string
 }
 
+// Device describes a resolved client, combining the coarse BasicDevice
+// classification with browser/OS identification and bot detection.
+type Device interface {
+	BasicDevice
+
+	// Browser returns the detected browser name and version, e.g. "Chrome", "91.0.4472.124".
+	// Both are empty when the browser could not be identified.
+	Browser() (name string, version string)
+
+	// OS returns the detected operating system name and version, e.g. "iOS", "15.0".
+	// Both are empty when the OS could not be identified.
+	OS() (name string, version string)
+
+	// Bot returns true when the User-Agent matches a known crawler/bot signature.
+	Bot() bool
+}
+
+// Option configures the Resolver middleware.
+type Option func(*resolverConfig)
+
+type resolverConfig struct {
+	contextKey string
+	botList    []string
+	resolve    func(header http.Header, botList []string) Device
+	cache      *Cache
+	cacheSet   bool
+	chAware    bool
+	custom     bool
+}
+
+// chAwareContextKey records, per request, whether Resolver() resolved the
+// Device using the Client-Hints-aware built-in resolver. Vary() reads it to
+// decide whether to add the Sec-CH-UA response headers alongside User-Agent.
+const chAwareContextKey = "github.com/floresj/go-contrib-mobile:ch-aware"
+
+// WithResolver overrides the function used to turn request headers into a
+// Device, replacing the built-in Client-Hints-then-UA-sniffing resolver
+// entirely. Since cacheKey only hashes the headers the built-in resolver
+// reads, it cannot safely memoize an arbitrary custom resolver's output (it
+// might consult a cookie, an auth header, geo-IP, ...); WithResolver
+// therefore disables Resolver()'s default cache unless the caller opts back
+// in with WithCache or WithCacheSize.
+func WithResolver(fn func(http.Header) Device) Option {
+	return func(c *resolverConfig) {
+		c.resolve = func(header http.Header, _ []string) Device { return fn(header) }
+		c.chAware = false
+		c.custom = true
+	}
+}
+
+// WithContextKey stores the resolved Device under key instead of DefaultKey.
+// Callers using a custom key must read it back with c.MustGet(key) directly,
+// since GetDevice always reads DefaultKey.
+func WithContextKey(key string) Option {
+	return func(c *resolverConfig) { c.contextKey = key }
+}
+
+// WithBotList overrides the product tokens the built-in resolver treats as
+// bots/crawlers. It has no effect when combined with WithResolver.
+func WithBotList(bots []string) Option {
+	return func(c *resolverConfig) { c.botList = bots }
+}
+
+// WithCacheSize bounds the number of resolved Devices Resolver() memoizes,
+// keyed by everything cacheKey folds in (User-Agent, X-Wap-Profile, Profile,
+// Accept, and the full Sec-CH-UA* set — see cacheKey for the exact list).
+// size <= 0 disables caching. Hold on to the Cache yourself and use WithCache
+// if you need to read CacheStats.
+func WithCacheSize(size int) Option {
+	return func(c *resolverConfig) { c.cache, c.cacheSet = NewCache(size), true }
+}
+
+// WithCache uses cache instead of Resolver()'s default-sized one, letting
+// callers read its Stats() for observability or share it across resolvers.
+// Combined with WithResolver, it also opts back into caching a custom
+// resolver's output, which Resolver() otherwise disables by default.
+func WithCache(cache *Cache) Option {
+	return func(c *resolverConfig) { c.cache, c.cacheSet = cache, true }
+}
+
 // Middleware function that parses the User-Agent and other Header properties to determine
-// the type of device being used.
-func Resolver() gin.HandlerFunc {
+// the type of device being used. By default it prefers User-Agent Client
+// Hints when the request carries them, falling back to legacy User-Agent
+// sniffing; see WithResolver to replace this behavior entirely.
+func Resolver(opts ...Option) gin.HandlerFunc {
+	cfg := &resolverConfig{
+		contextKey: DefaultKey,
+		botList:    DefaultBotList,
+		resolve:    defaultResolve,
+		cache:      NewCache(DefaultCacheSize),
+		chAware:    true,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.custom && !cfg.cacheSet {
+		cfg.cache = nil
+	}
+
 	return func(c *gin.Context) {
-		d := resolveDevice(c.Request.Header)
-		c.Set(DefaultKey, d)
+		if cfg.chAware {
+			advertiseClientHints(c)
+		}
+		c.Set(chAwareContextKey, cfg.chAware)
+
+		key := cacheKey(c.Request.Header)
+		d, ok := cfg.cache.get(key)
+		if !ok {
+			d = cfg.resolve(c.Request.Header, cfg.botList)
+			cfg.cache.put(key, d)
+		}
+
+		c.Set(cfg.contextKey, d)
 		c.Next()
 	}
 }
 
+// defaultResolve prefers User-Agent Client Hints when present and falls back
+// to User-Agent sniffing otherwise. Bot detection always runs against the
+// User-Agent, since Client Hints carry no bot signal.
+func defaultResolve(header http.Header, botList []string) Device {
+	if d, ok := resolveFromClientHints(header); ok {
+		agent := header.Get("User-Agent")
+		d.bot = detectBot(agent, productVersionRE.FindAllStringSubmatch(agent, -1), botList)
+		return d
+	}
+	return resolveDevice(header, botList)
+}
+
 // Reads the Header from a Request and attempts to determine what type of device the user is using.
 // Utilizes various checks using the User-Agent,
-func resolveDevice(header http.Header) Device {
+func resolveDevice(header http.Header, botList []string) Device {
+	d := classifyDevice(header)
+	parseUserAgentInto(d, header.Get("User-Agent"), botList)
+	return d
+}
+
+// classifyDevice runs the original coarse mobile/tablet/normal + platform
+// classification. It is split out from resolveDevice so the richer UA
+// parsing (browser, OS, bot) in useragent.go can be layered on top without
+// disturbing this logic.
+func classifyDevice(header http.Header) *device {
 	agent := strings.ToLower(header.Get("User-Agent"))
 
 	// Check Tablet
@@ -171,3 +310,18 @@ func (d *device) Tablet() bool {
 func (d *device) Platform() string {
 	return d.platform
 }
+
+// Browser returns the detected browser name and version, e.g. "Chrome", "91.0.4472.124".
+func (d *device) Browser() (name string, version string) {
+	return d.browserName, d.browserVersion
+}
+
+// OS returns the detected operating system name and version, e.g. "iOS", "15.0".
+func (d *device) OS() (name string, version string) {
+	return d.osName, d.osVersion
+}
+
+// Bot returns true when the User-Agent matched a known crawler/bot signature.
+func (d *device) Bot() bool {
+	return d.bot
+}
@@ -0,0 +1,77 @@
+package mobile
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Vary returns a gin.HandlerFunc that adds "Vary: User-Agent" to every
+// response so shared caches/CDNs don't serve one device class's response to
+// another. When registered after a Resolver() using the built-in
+// Client-Hints-aware resolver, it also adds the Sec-CH-UA family so caches
+// key on whichever signal actually drove the device resolution. Register it
+// after Resolver().
+func Vary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "User-Agent")
+		if chAware, _ := c.Get(chAwareContextKey); chAware == true {
+			c.Writer.Header().Add("Vary", HeaderSecCHUA)
+			c.Writer.Header().Add("Vary", HeaderSecCHUAMobile)
+			c.Writer.Header().Add("Vary", HeaderSecCHUAPlatform)
+		}
+		c.Next()
+	}
+}
+
+// DeviceETag folds d's device class into base, an existing ETag value (with
+// or without surrounding quotes), so that mobile/tablet/desktop variants of
+// the same URL get distinct ETags and don't collide in shared caches/CDNs.
+func DeviceETag(d Device, base string) string {
+	class := deviceClass(d)
+	if class == "" {
+		return base
+	}
+	if strings.HasSuffix(base, `"`) {
+		return base[:len(base)-1] + "-" + class + `"`
+	}
+	return base + "-" + class
+}
+
+// deviceClass returns d's coarse class as used by DeviceETag and
+// NegotiateDevice: "bot", "mobile", "tablet", "desktop", or "" if none apply.
+func deviceClass(d Device) string {
+	switch {
+	case d.Bot():
+		return "bot"
+	case d.Mobile():
+		return "mobile"
+	case d.Tablet():
+		return "tablet"
+	case d.Normal():
+		return "desktop"
+	default:
+		return ""
+	}
+}
+
+// NegotiateDevice calls the variant in variants matching c's resolved Device
+// class ("mobile", "tablet", "desktop" or "bot"), falling back to "desktop"
+// and then to "mobile", "tablet" and "bot" in that order, so handlers don't
+// need their own Mobile()/Tablet()/Normal() switch. Keys other than these four
+// are never called. Each entry in variants must be a func().
+func NegotiateDevice(c *gin.Context, variants gin.H) {
+	class := deviceClass(GetDevice(c))
+
+	for _, key := range []string{class, "desktop", "mobile", "tablet", "bot"} {
+		if key == "" {
+			continue
+		}
+		render, ok := variants[key].(func())
+		if !ok {
+			continue
+		}
+		render()
+		return
+	}
+}
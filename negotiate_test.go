@@ -0,0 +1,131 @@
+package mobile
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestVaryAddsClientHintsWhenCHAwareResolverIsActive(t *testing.T) {
+	r := gin.New()
+	r.Use(Resolver(), Vary())
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", desktopUA)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	want := []string{"User-Agent", HeaderSecCHUA, HeaderSecCHUAMobile, HeaderSecCHUAPlatform}
+	got := w.Header().Values("Vary")
+	if len(got) != len(want) {
+		t.Fatalf("Vary = %v, want %v", got, want)
+	}
+	for i, v := range want {
+		if got[i] != v {
+			t.Errorf("Vary[%d] = %q, want %q", i, got[i], v)
+		}
+	}
+}
+
+func TestVaryOmitsClientHintsWithCustomResolver(t *testing.T) {
+	r := gin.New()
+	r.Use(Resolver(WithResolver(func(h http.Header) Device { return &device{normal: true} })), Vary())
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	want := []string{"User-Agent"}
+	got := w.Header().Values("Vary")
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("Vary = %v, want %v", got, want)
+	}
+}
+
+func TestDeviceETag(t *testing.T) {
+	cases := []struct {
+		name string
+		d    Device
+		base string
+		want string
+	}{
+		{"mobile quoted", &device{mobile: true}, `"abc123"`, `"abc123-mobile"`},
+		{"tablet unquoted", &device{tablet: true}, "abc123", "abc123-tablet"},
+		{"desktop (Normal)", &device{normal: true}, `"abc123"`, `"abc123-desktop"`},
+		{"bot takes priority over mobile", &device{bot: true, mobile: true}, `"abc123"`, `"abc123-bot"`},
+		{"unclassified device leaves base untouched", &device{}, `"abc123"`, `"abc123"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DeviceETag(tc.d, tc.base); got != tc.want {
+				t.Errorf("DeviceETag() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func newNegotiateContext(d Device) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Set(DefaultKey, d)
+	return c, w
+}
+
+func TestNegotiateDeviceCallsMatchingVariant(t *testing.T) {
+	c, _ := newNegotiateContext(&device{mobile: true})
+
+	called := ""
+	NegotiateDevice(c, gin.H{
+		"mobile":  func() { called = "mobile" },
+		"desktop": func() { called = "desktop" },
+	})
+
+	if called != "mobile" {
+		t.Errorf("called = %q, want %q", called, "mobile")
+	}
+}
+
+func TestNegotiateDeviceFallsBackToDesktop(t *testing.T) {
+	c, _ := newNegotiateContext(&device{tablet: true})
+
+	called := ""
+	NegotiateDevice(c, gin.H{
+		"desktop": func() { called = "desktop" },
+	})
+
+	if called != "desktop" {
+		t.Errorf("called = %q, want %q (tablet variant missing, should fall back to desktop)", called, "desktop")
+	}
+}
+
+func TestNegotiateDeviceFallsBackToAnyPresentVariant(t *testing.T) {
+	c, _ := newNegotiateContext(&device{bot: true})
+
+	called := ""
+	NegotiateDevice(c, gin.H{
+		"tablet": func() { called = "tablet" },
+	})
+
+	if called != "tablet" {
+		t.Errorf("called = %q, want %q (no bot/desktop/mobile variant, should fall through to whatever is present)", called, "tablet")
+	}
+}
+
+func TestNegotiateDeviceNoMatchingVariantCallsNothing(t *testing.T) {
+	c, _ := newNegotiateContext(&device{mobile: true})
+
+	called := false
+	NegotiateDevice(c, gin.H{
+		"not-a-real-class": func() { called = true },
+	})
+
+	if called {
+		t.Error("expected no variant to be called when none of the fallback classes are present")
+	}
+}
@@ -0,0 +1,126 @@
+package mobile
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Query parameter and cookie names used by SiteSwitcher to let users opt out
+// of automatic device-class routing, mirroring spring-mobile's SitePreference.
+const (
+	SiteQueryParam = "site"
+	SiteCookieName = "site_preference"
+
+	SiteFull   = "full"
+	SiteMobile = "mobile"
+	SiteTablet = "tablet"
+)
+
+// Require returns a gin.HandlerFunc that aborts the request with status
+// (http.StatusNotFound by default) when predicate returns false for the
+// resolved Device. It must be registered after Resolver().
+func Require(predicate func(Device) bool, status ...int) gin.HandlerFunc {
+	code := http.StatusNotFound
+	if len(status) > 0 {
+		code = status[0]
+	}
+	return func(c *gin.Context) {
+		if !predicate(GetDevice(c)) {
+			c.AbortWithStatus(code)
+			return
+		}
+		c.Next()
+	}
+}
+
+// OnlyMobile restricts a route to mobile devices, aborting with status
+// (404 by default) for everyone else.
+func OnlyMobile(status ...int) gin.HandlerFunc {
+	return Require(func(d Device) bool { return d.Mobile() }, status...)
+}
+
+// OnlyTablet restricts a route to tablets, aborting with status (404 by
+// default) for everyone else.
+func OnlyTablet(status ...int) gin.HandlerFunc {
+	return Require(func(d Device) bool { return d.Tablet() }, status...)
+}
+
+// OnlyDesktop restricts a route to normal (non-mobile, non-tablet) devices,
+// aborting with status (404 by default) for everyone else.
+func OnlyDesktop(status ...int) gin.HandlerFunc {
+	return Require(func(d Device) bool { return d.Normal() }, status...)
+}
+
+// OnlyBots restricts a route to known crawlers/bots, aborting with status
+// (404 by default) for everyone else.
+func OnlyBots(status ...int) gin.HandlerFunc {
+	return Require(func(d Device) bool { return d.Bot() }, status...)
+}
+
+// Redirect returns a gin.HandlerFunc that issues an HTTP redirect to target
+// for any request whose Device matches predicate, the common "send mobile
+// UAs to m.example.com" pattern. Requests that don't match fall through
+// unchanged. It must be registered after Resolver().
+func Redirect(target string, predicate func(Device) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if predicate(GetDevice(c)) {
+			c.Redirect(http.StatusFound, target)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// SiteSwitcher lets users override automatic device-class routing via a
+// `?site=full|mobile|tablet` query parameter, persisted as a cookie for
+// subsequent requests, the equivalent of spring-mobile's SitePreference. It
+// re-resolves the override after Resolver() has already run and must
+// therefore be registered after it in the middleware chain. contextKey
+// optionally overrides which context key it reads/overwrites, matching
+// whatever key Resolver() was configured with via WithContextKey; it
+// defaults to DefaultKey.
+func SiteSwitcher(contextKey ...string) gin.HandlerFunc {
+	key := DefaultKey
+	if len(contextKey) > 0 {
+		key = contextKey[0]
+	}
+
+	return func(c *gin.Context) {
+		override := c.Query(SiteQueryParam)
+		if override == "" {
+			if cookie, err := c.Cookie(SiteCookieName); err == nil {
+				override = cookie
+			}
+		}
+
+		switch override {
+		case SiteFull, SiteMobile, SiteTablet:
+			c.SetCookie(SiteCookieName, override, 0, "/", "", false, true)
+		default:
+			c.Next()
+			return
+		}
+
+		var d *device
+		if existing, ok := c.Get(key); ok {
+			d, _ = existing.(*device)
+		}
+		if d == nil {
+			d = &device{}
+		}
+		overridden := *d
+		overridden.normal, overridden.mobile, overridden.tablet = false, false, false
+		switch override {
+		case SiteFull:
+			overridden.normal = true
+		case SiteMobile:
+			overridden.mobile = true
+		case SiteTablet:
+			overridden.tablet = true
+		}
+		c.Set(key, &overridden)
+		c.Next()
+	}
+}
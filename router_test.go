@@ -0,0 +1,162 @@
+package mobile
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// desktopUA is a plain desktop Chrome User-Agent with no Client Hints, so
+// Resolver() falls back to UA sniffing and classifies it as "normal".
+const desktopUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+
+func newSiteSwitcherEngine(keys ...string) *gin.Engine {
+	r := gin.New()
+	r.Use(Resolver(), SiteSwitcher(keys...))
+	r.GET("/", func(c *gin.Context) {
+		key := DefaultKey
+		if len(keys) > 0 {
+			key = keys[0]
+		}
+		d := c.MustGet(key).(Device)
+		switch {
+		case d.Mobile():
+			c.String(http.StatusOK, "mobile")
+		case d.Tablet():
+			c.String(http.StatusOK, "tablet")
+		case d.Normal():
+			c.String(http.StatusOK, "desktop")
+		default:
+			c.String(http.StatusOK, "unknown")
+		}
+	})
+	return r
+}
+
+func TestSiteSwitcherQueryOverride(t *testing.T) {
+	cases := []struct {
+		site string
+		want string
+	}{
+		{SiteFull, "desktop"},
+		{SiteMobile, "mobile"},
+		{SiteTablet, "tablet"},
+	}
+
+	r := newSiteSwitcherEngine()
+	for _, tc := range cases {
+		t.Run(tc.site, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/?site="+tc.site, nil)
+			req.Header.Set("User-Agent", desktopUA)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Body.String() != tc.want {
+				t.Errorf("body = %q, want %q", w.Body.String(), tc.want)
+			}
+
+			cookies := w.Result().Cookies()
+			if len(cookies) != 1 || cookies[0].Name != SiteCookieName || cookies[0].Value != tc.site {
+				t.Errorf("cookies = %+v, want a single %s=%s cookie", cookies, SiteCookieName, tc.site)
+			}
+		})
+	}
+}
+
+func TestSiteSwitcherCookieReadBack(t *testing.T) {
+	r := newSiteSwitcherEngine()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", desktopUA)
+	req.AddCookie(&http.Cookie{Name: SiteCookieName, Value: SiteMobile})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "mobile" {
+		t.Errorf("body = %q, want %q (override from cookie, no query param)", w.Body.String(), "mobile")
+	}
+}
+
+func TestSiteSwitcherInvalidOverrideFallsThrough(t *testing.T) {
+	r := newSiteSwitcherEngine()
+
+	req := httptest.NewRequest(http.MethodGet, "/?site=bogus", nil)
+	req.Header.Set("User-Agent", desktopUA)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "desktop" {
+		t.Errorf("body = %q, want %q (invalid override should leave Resolver()'s classification untouched)", w.Body.String(), "desktop")
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("expected no cookie to be set for an invalid override, got %+v", w.Result().Cookies())
+	}
+}
+
+func TestSiteSwitcherHonorsCustomContextKey(t *testing.T) {
+	const customKey = "custom-device-key"
+	r := newSiteSwitcherEngine(customKey)
+
+	req := httptest.NewRequest(http.MethodGet, "/?site=tablet", nil)
+	req.Header.Set("User-Agent", desktopUA)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "tablet" {
+		t.Errorf("body = %q, want %q (override must apply under the custom context key)", w.Body.String(), "tablet")
+	}
+}
+
+func TestRequireAbortsNonMatchingDevice(t *testing.T) {
+	r := gin.New()
+	r.Use(Resolver())
+	r.GET("/", OnlyMobile(), func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", desktopUA)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRequireCustomStatus(t *testing.T) {
+	r := gin.New()
+	r.Use(Resolver())
+	r.GET("/", OnlyMobile(http.StatusForbidden), func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", desktopUA)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestRedirect(t *testing.T) {
+	r := gin.New()
+	r.Use(Resolver(), Redirect("https://m.example.com", func(d Device) bool { return d.Normal() }))
+	r.GET("/", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", desktopUA)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusFound)
+	}
+	if loc := w.Header().Get("Location"); loc != "https://m.example.com" {
+		t.Errorf("Location = %q, want %q", loc, "https://m.example.com")
+	}
+}
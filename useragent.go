@@ -0,0 +1,170 @@
+package mobile
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultBotList is the set of crawler/bot product tokens checked by the
+// built-in resolver. Matching is a case-insensitive substring check against
+// the whole User-Agent string.
+var DefaultBotList = []string{
+	"googlebot", "bingbot", "slurp", "duckduckbot", "baiduspider",
+	"yandexbot", "sogou", "exabot", "ia_archiver", "facebookexternalhit",
+	"twitterbot", "applebot", "linkedinbot", "whatsapp", "telegrambot",
+	"discordbot", "slackbot", "pinterest", "rogerbot", "semrushbot",
+	"ahrefsbot", "mj12bot", "dotbot", "petalbot",
+}
+
+// productVersionRE matches a "Product/Version" token, e.g. "Chrome/91.0.4472.124".
+var productVersionRE = regexp.MustCompile(`([A-Za-z][\w.\-]*)/([0-9][\w.\-]*)`)
+
+// firstCommentRE extracts the content of the first parenthesized comment
+// block in a User-Agent string, e.g. "(Windows NT 10.0; Win64; x64)".
+var firstCommentRE = regexp.MustCompile(`\(([^)]*)\)`)
+
+// urlInCommentRE is a heuristic for bots that advertise a homepage URL in a
+// UA comment, e.g. "(+http://www.google.com/bot.html)".
+var urlInCommentRE = regexp.MustCompile(`https?://`)
+
+// osMatcher pairs a pattern found in the first UA comment block with the
+// OS name/version it implies. Entries are tried in order, so more specific
+// patterns (iOS before generic "Mac OS X") must come first.
+type osMatcher struct {
+	pattern *regexp.Regexp
+	name    string
+	// versionGroup, if > 0, picks the submatch of pattern holding the
+	// version, which normalizeVersion cleans up; 0 means no version.
+	versionGroup int
+}
+
+var osMatchers = []osMatcher{
+	{regexp.MustCompile(`CPU (?:iPhone )?OS ([\d_]+) like Mac OS X`), "iOS", 1},
+	{regexp.MustCompile(`Android ([\d.]+)`), "Android", 1},
+	{regexp.MustCompile(`Windows NT 10\.0`), "Windows 10", 0},
+	{regexp.MustCompile(`Windows NT 6\.3`), "Windows 8.1", 0},
+	{regexp.MustCompile(`Windows NT 6\.2`), "Windows 8", 0},
+	{regexp.MustCompile(`Windows NT 6\.1`), "Windows 7", 0},
+	{regexp.MustCompile(`Windows NT 6\.0`), "Windows Vista", 0},
+	{regexp.MustCompile(`Windows NT 5\.1`), "Windows XP", 0},
+	{regexp.MustCompile(`Mac OS X ([\d_]+)`), "macOS", 1},
+	{regexp.MustCompile(`CrOS`), "Chrome OS", 0},
+	{regexp.MustCompile(`Linux`), "Linux", 0},
+}
+
+// browserEngines lists the product tokens that identify a browser engine, in
+// priority order: when several are present (as is common, since most
+// browsers impersonate Chrome/Safari for compatibility) the first match in
+// this list wins.
+var browserEngines = []struct {
+	token string
+	name  string
+}{
+	{"Edg", "Edge"},
+	{"EdgA", "Edge"},
+	{"OPR", "Opera"},
+	{"SamsungBrowser", "Samsung Internet"},
+	{"Firefox", "Firefox"},
+	{"Chrome", "Chrome"},
+	{"CriOS", "Chrome"},
+	{"FxiOS", "Firefox"},
+	{"MSIE", "Internet Explorer"},
+	{"Trident", "Internet Explorer"},
+	{"Safari", "Safari"},
+}
+
+// parseUserAgentInto populates the browser, OS and bot fields of d from the
+// raw (mixed-case) User-Agent string. It tokenizes the UA into Product/Version
+// groups plus the leading parenthesized comment, the same general approach
+// taken by mssola/user_agent and mileusna/useragent. botList overrides
+// DefaultBotList when matching crawler signatures.
+func parseUserAgentInto(d *device, agent string, botList []string) {
+	if agent == "" {
+		return
+	}
+
+	tokens := productVersionRE.FindAllStringSubmatch(agent, -1)
+
+	d.bot = detectBot(agent, tokens, botList)
+
+	if name, version, ok := detectOS(agent); ok {
+		d.osName = name
+		d.osVersion = version
+	}
+
+	if name, version, ok := detectBrowser(tokens); ok {
+		d.browserName = name
+		d.browserVersion = version
+	}
+}
+
+// detectOS matches the first parenthesized comment block against osMatchers.
+func detectOS(agent string) (name string, version string, ok bool) {
+	comment := agent
+	if m := firstCommentRE.FindStringSubmatch(agent); m != nil {
+		comment = m[1]
+	}
+
+	for _, m := range osMatchers {
+		sub := m.pattern.FindStringSubmatch(comment)
+		if sub == nil {
+			continue
+		}
+		if m.versionGroup > 0 {
+			return m.name, normalizeVersion(sub[m.versionGroup]), true
+		}
+		return m.name, "", true
+	}
+	return "", "", false
+}
+
+// normalizeVersion turns underscore-separated version components (as used by
+// Apple platforms, e.g. "15_0_1") into dotted ones ("15.0.1").
+func normalizeVersion(v string) string {
+	return strings.ReplaceAll(v, "_", ".")
+}
+
+// detectBrowser walks browserEngines in priority order and returns the
+// version recorded for the first matching token. Safari's real version is
+// carried in a separate "Version/x.y" token rather than "Safari/x.y", so it
+// is special-cased.
+func detectBrowser(tokens [][]string) (name string, version string, ok bool) {
+	byProduct := make(map[string]string, len(tokens))
+	for _, t := range tokens {
+		byProduct[t[1]] = t[2]
+	}
+
+	for _, engine := range browserEngines {
+		v, found := byProduct[engine.token]
+		if !found {
+			continue
+		}
+		if engine.name == "Safari" {
+			if sv, ok := byProduct["Version"]; ok {
+				v = sv
+			}
+		}
+		return engine.name, v, true
+	}
+	return "", "", false
+}
+
+// detectBot reports whether the UA matches a known bot/crawler signature:
+// an entry of botList appearing anywhere in the UA, a product token whose
+// name contains "bot", or a URL in the UA (a common convention for
+// well-behaved crawlers) together with "bot" anywhere in it. A URL alone
+// does not mark the UA as a bot.
+func detectBot(agent string, tokens [][]string, botList []string) bool {
+	lower := strings.ToLower(agent)
+	for _, bot := range botList {
+		if strings.Contains(lower, bot) {
+			return true
+		}
+	}
+	for _, t := range tokens {
+		if strings.Contains(strings.ToLower(t[1]), "bot") {
+			return true
+		}
+	}
+	return urlInCommentRE.MatchString(agent) && strings.Contains(lower, "bot")
+}
@@ -0,0 +1,94 @@
+package mobile
+
+import "testing"
+
+func TestParseUserAgentInto(t *testing.T) {
+	cases := []struct {
+		name           string
+		agent          string
+		wantBrowser    string
+		wantBrowserVer string
+		wantOS         string
+		wantOSVer      string
+		wantBot        bool
+	}{
+		{
+			name:           "windows chrome",
+			agent:          "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36",
+			wantBrowser:    "Chrome",
+			wantBrowserVer: "91.0.4472.124",
+			wantOS:         "Windows 10",
+			wantOSVer:      "",
+		},
+		{
+			name:           "macos safari uses the Version token for its version",
+			agent:          "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Safari/605.1.15",
+			wantBrowser:    "Safari",
+			wantBrowserVer: "16.5",
+			wantOS:         "macOS",
+			wantOSVer:      "10.15.7",
+		},
+		{
+			name:           "iphone safari",
+			agent:          "Mozilla/5.0 (iPhone; CPU iPhone OS 15_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/15.0 Mobile/15E148 Safari/604.1",
+			wantBrowser:    "Safari",
+			wantBrowserVer: "15.0",
+			wantOS:         "iOS",
+			wantOSVer:      "15.0",
+		},
+		{
+			name:           "android chrome",
+			agent:          "Mozilla/5.0 (Linux; Android 12; Pixel 6) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.120 Mobile Safari/537.36",
+			wantBrowser:    "Chrome",
+			wantBrowserVer: "91.0.4472.120",
+			wantOS:         "Android",
+			wantOSVer:      "12",
+		},
+		{
+			name:           "edge prefers Edg token over Chrome",
+			agent:          "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36 Edg/91.0.864.59",
+			wantBrowser:    "Edge",
+			wantBrowserVer: "91.0.864.59",
+			wantOS:         "Windows 10",
+		},
+		{
+			name:    "googlebot is flagged as a bot",
+			agent:   "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			wantBot: true,
+		},
+		{
+			name:           "plain firefox is not a bot",
+			agent:          "Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0",
+			wantBrowser:    "Firefox",
+			wantBrowserVer: "115.0",
+			wantOS:         "Linux",
+			wantBot:        false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &device{}
+			parseUserAgentInto(d, tc.agent, DefaultBotList)
+
+			if d.browserName != tc.wantBrowser || d.browserVersion != tc.wantBrowserVer {
+				t.Errorf("browser = (%q, %q), want (%q, %q)", d.browserName, d.browserVersion, tc.wantBrowser, tc.wantBrowserVer)
+			}
+			if d.osName != tc.wantOS || d.osVersion != tc.wantOSVer {
+				t.Errorf("os = (%q, %q), want (%q, %q)", d.osName, d.osVersion, tc.wantOS, tc.wantOSVer)
+			}
+			if d.bot != tc.wantBot {
+				t.Errorf("bot = %v, want %v", d.bot, tc.wantBot)
+			}
+		})
+	}
+}
+
+func TestParseUserAgentIntoHonorsCustomBotList(t *testing.T) {
+	d := &device{}
+	parseUserAgentInto(d, "Mozilla/5.0 (compatible; SuperCrawler/1.0)", []string{"supercrawler"})
+
+	if !d.bot {
+		t.Fatal("expected SuperCrawler to be flagged as a bot via a custom bot list")
+	}
+}